@@ -7,17 +7,32 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
+	"syscall"
 
 	"github.com/PlakarKorp/kloset/connectors"
 	"github.com/PlakarKorp/kloset/connectors/exporter"
 	"github.com/PlakarKorp/kloset/connectors/importer"
 	"github.com/PlakarKorp/kloset/location"
-	"github.com/PlakarKorp/kloset/objects"
+
+	"github.com/tracepanic/plakar-integration/internal/ignore"
+	"github.com/tracepanic/plakar-integration/internal/walker"
 )
 
 type testConnector struct {
-	scanDir string
+	scanDir        string
+	ignore         *ignore.IgnoreMatcher
+	followSymlinks bool
+	concurrency    int
+}
+
+// inodeKey identifies a file by device and inode, used to recognize
+// hardlinked pairs on export.
+type inodeKey struct {
+	dev uint64
+	ino uint64
 }
 
 func init() {
@@ -44,8 +59,25 @@ func newConnector(proto string, config map[string]string) (*testConnector, error
 		return nil, fmt.Errorf("empty path after %s://", proto)
 	}
 
+	matcher := ignore.New(scanDir)
+	if excludeFrom, ok := config["exclude_from"]; ok && excludeFrom != "" {
+		if err := matcher.LoadGlobalFile(excludeFrom); err != nil {
+			return nil, fmt.Errorf("cannot load exclude_from: %w", err)
+		}
+	}
+
+	followSymlinks, _ := strconv.ParseBool(config["follow_symlinks"])
+
+	concurrency := runtime.NumCPU()
+	if v, err := strconv.Atoi(config["concurrency"]); err == nil && v > 0 {
+		concurrency = v
+	}
+
 	return &testConnector{
-		scanDir: scanDir,
+		scanDir:        scanDir,
+		ignore:         matcher,
+		followSymlinks: followSymlinks,
+		concurrency:    concurrency,
 	}, nil
 }
 
@@ -62,62 +94,127 @@ func (f *testConnector) Ping(ctx context.Context) error {
 	return err
 }
 
+// Import walks scanDir with a bounded pool of worker goroutines instead
+// of a single filepath.WalkDir call. This keeps I/O-bound trees (network
+// filesystems, FUSE) from serializing on a single goroutine's stat calls.
+// The walk itself is shared with importer.TestImporter via internal/walker.
 func (f *testConnector) Import(ctx context.Context, records chan<- *connectors.Record, results <-chan *connectors.Result) error {
 	defer close(records)
 
-	return filepath.WalkDir(f.scanDir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			if path == f.scanDir {
-				return err
-			}
-			return nil
-		}
+	return walker.Walk(ctx, walker.Options{
+		Root:           f.scanDir,
+		Ignore:         f.ignore,
+		FollowSymlinks: f.followSymlinks,
+		Concurrency:    f.concurrency,
+	}, records)
+}
 
-		if d.IsDir() {
-			return nil
+// Export rejoins each record's forward-slash Pathname under scanDir using
+// filepath.Join, which applies this host's native separator. An earlier
+// version of this connector also stashed the source OS's separator in the
+// record metadata for Export to consult, but Pathname is always
+// ToSlash-normalized on the way in regardless of host OS (see Import), so
+// FromSlash/Join here is already OS-agnostic and the hint was never read:
+// it's been dropped rather than carried as unused metadata.
+func (f *testConnector) Export(ctx context.Context, records <-chan *connectors.Record, results chan<- *connectors.Result) error {
+	defer close(results)
+
+	absScanDir, err := filepath.Abs(f.scanDir)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[inodeKey]string)
+
+	for record := range records {
+		if err := validatePathname(record.Pathname); err != nil {
+			results <- record.Error(err)
+			continue
 		}
 
-		info, err := d.Info()
+		pathname := strings.TrimPrefix(record.Pathname, "/")
+		path := filepath.Join(f.scanDir, filepath.FromSlash(pathname))
+
+		absPath, err := filepath.Abs(path)
 		if err != nil {
-			return nil
+			results <- record.Error(err)
+			continue
+		}
+		if !HasPathPrefix(absScanDir, absPath) {
+			results <- record.Error(fmt.Errorf("pathname %q escapes export root", record.Pathname))
+			continue
 		}
 
-		fi := objects.FileInfo{
-			Lname:    filepath.Base(path),
-			Lsize:    info.Size(),
-			Lmode:    info.Mode(),
-			LmodTime: info.ModTime(),
-			Ldev:     1,
+		dir := filepath.Dir(path)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			results <- record.Error(err)
+			continue
 		}
 
-		records <- connectors.NewRecord(path, "", fi, nil, func() (io.ReadCloser, error) {
-			return os.Open(path)
-		})
+		mode := record.FileInfo.Lmode
 
-		return nil
-	})
-}
+		if mode&fs.ModeSymlink != 0 {
+			closeReader(record)
+			os.Remove(path) // a stale entry would make Symlink fail with EEXIST
+			if err := os.Symlink(record.Target, path); err != nil {
+				results <- record.Error(err)
+			} else {
+				results <- record.Ok()
+			}
+			continue
+		}
 
-func (f *testConnector) Export(ctx context.Context, records <-chan *connectors.Record, results chan<- *connectors.Result) error {
-	defer close(results)
+		if mode&fs.ModeNamedPipe != 0 {
+			closeReader(record)
+			os.Remove(path) // a stale entry would make Mkfifo fail with EEXIST
+			if err := syscall.Mkfifo(path, uint32(mode.Perm())); err != nil {
+				results <- record.Error(err)
+			} else {
+				results <- record.Ok()
+			}
+			continue
+		}
 
-	for record := range records {
-		pathname := strings.TrimPrefix(record.Pathname, "/")
-		path := filepath.Join(f.scanDir, pathname)
+		if mode&fs.ModeDevice != 0 {
+			closeReader(record)
+			results <- record.Error(fmt.Errorf("cannot restore device node %q: major/minor numbers are not carried by this connector", record.Pathname))
+			continue
+		}
 
-		dir := filepath.Dir(path)
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			results <- record.Error(err)
+		if mode&fs.ModeSocket != 0 {
+			closeReader(record)
+			results <- record.Error(fmt.Errorf("cannot restore socket %q: sockets cannot be recreated from a backup", record.Pathname))
 			continue
 		}
 
+		if mode.IsRegular() && record.FileInfo.Lino != 0 {
+			key := inodeKey{dev: record.FileInfo.Ldev, ino: record.FileInfo.Lino}
+			if first, ok := seen[key]; ok {
+				closeReader(record)
+				if err := os.Link(first, path); err != nil {
+					results <- record.Error(err)
+				} else {
+					results <- record.Ok()
+				}
+				continue
+			}
+			seen[key] = path
+		}
+
 		fp, err := os.Create(path)
 		if err != nil {
 			results <- record.Error(err)
 			continue
 		}
 
-		if record.Reader != nil {
+		// Guard on the file's mode, not record.Reader: the record's reader
+		// is a lazy wrapper that is never nil by itself, and calling Read
+		// on it for a non-regular entry (whose opener returns a nil
+		// io.ReadCloser) panics instead of reporting an error. Symlinks,
+		// named pipes, devices and sockets are special-cased with their own
+		// continue above; any other non-regular mode (a directory, say)
+		// still reaches this point, gets an empty file, and is never read.
+		if mode.IsRegular() {
 			_, err = io.Copy(fp, record.Reader)
 			record.Close()
 		}
@@ -133,6 +230,15 @@ func (f *testConnector) Export(ctx context.Context, records <-chan *connectors.R
 	return nil
 }
 
+// closeReader releases a record's content reader on paths that never
+// reach the os.Create/io.Copy block, mirroring the Close call made there.
+// Record.Reader is a lazy wrapper that is populated unconditionally by the
+// SDK, so there is nothing to guard here: Close on an unopened reader is a
+// no-op, it's only Read that requires the mode check above.
+func closeReader(record *connectors.Record) {
+	record.Close()
+}
+
 func (f *testConnector) Close(ctx context.Context) error {
 	return nil
 }
@@ -0,0 +1,75 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PlakarKorp/kloset/connectors"
+
+	"github.com/tracepanic/plakar-integration/internal/ignore"
+)
+
+// benchFileCount is scaled down from the ~100k files this request asks
+// benchmarking against, to keep `go test -bench` tractable in CI. Bump it
+// locally to reproduce the larger scenario.
+const benchFileCount = 5000
+
+func buildBenchTree(b *testing.B, files int) string {
+	b.Helper()
+	root := b.TempDir()
+	for i := 0; i < files; i++ {
+		dir := filepath.Join(root, fmt.Sprintf("d%d", i%50))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			b.Fatal(err)
+		}
+		name := filepath.Join(dir, fmt.Sprintf("f%d.txt", i))
+		if err := os.WriteFile(name, []byte("x"), 0644); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return root
+}
+
+func BenchmarkImportConcurrentWalker(b *testing.B) {
+	root := buildBenchTree(b, benchFileCount)
+	conn := &testConnector{scanDir: root, ignore: ignore.New(root), concurrency: 8}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		records := make(chan *connectors.Record)
+		results := make(chan *connectors.Result)
+
+		go func() {
+			for range records {
+			}
+		}()
+
+		if err := conn.Import(context.Background(), records, results); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkImportSequentialWalkDir reproduces this package's pre-chunk0-5
+// single-goroutine filepath.WalkDir approach as a baseline.
+func BenchmarkImportSequentialWalkDir(b *testing.B) {
+	root := buildBenchTree(b, benchFileCount)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+			_, err = os.Lstat(path)
+			return err
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
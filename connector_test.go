@@ -0,0 +1,116 @@
+package connector
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PlakarKorp/kloset/connectors"
+	"github.com/PlakarKorp/kloset/objects"
+
+	"github.com/tracepanic/plakar-integration/internal/ignore"
+)
+
+// emptyReader is the opener a regular-file record carries when there's no
+// real backing file to read from; it's what Export's io.Copy actually
+// drains, so tests that only care about path handling still need a live
+// reader rather than the (nil, nil) a symlink/device record would return.
+func emptyReader() (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(nil)), nil
+}
+
+func TestImportNormalizesPathnameToForwardSlashes(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "sub", "dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "sub", "dir", "file.txt"), []byte("hi"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	conn := &testConnector{scanDir: root, ignore: ignore.New(root), concurrency: 2}
+
+	records := make(chan *connectors.Record)
+	results := make(chan *connectors.Result)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- conn.Import(context.Background(), records, results) }()
+
+	var got []string
+	for r := range records {
+		got = append(got, r.Pathname)
+	}
+	if err := <-errCh; err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+
+	want := "sub/dir/file.txt"
+	var found bool
+	for _, p := range got {
+		if p != filepath.ToSlash(p) {
+			t.Errorf("pathname %q is not forward-slash normalized", p)
+		}
+		if filepath.IsAbs(p) {
+			t.Errorf("pathname %q is absolute, want relative to scanDir", p)
+		}
+		if p == want {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected %q among imported pathnames, got %v", want, got)
+	}
+}
+
+// TestExportJoinsForwardSlashPathnameNatively simulates exporting a
+// record produced by an import on the other OS: the Pathname is already
+// forward-slash normalized (as Import always does, regardless of host
+// OS), and Export must rejoin it using this host's native separator.
+func TestExportJoinsForwardSlashPathnameNatively(t *testing.T) {
+	root := t.TempDir()
+	conn := &testConnector{scanDir: root, ignore: ignore.New(root)}
+
+	records := make(chan *connectors.Record, 1)
+	results := make(chan *connectors.Result, 1)
+
+	fi := objects.FileInfo{Lname: "file.txt"}
+	records <- connectors.NewRecord("sub/dir/file.txt", "", fi, nil, emptyReader)
+	close(records)
+
+	if err := conn.Export(context.Background(), records, results); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	want := filepath.Join(root, "sub", "dir", "file.txt")
+	if _, err := os.Stat(want); err != nil {
+		t.Fatalf("expected exported file at %s: %v", want, err)
+	}
+}
+
+// TestExportPreservesLiteralBackslashInFilename guards against treating a
+// backslash that is part of a filename (legal on Unix) as a path
+// separator when rejoining a forward-slash-normalized Pathname.
+func TestExportPreservesLiteralBackslashInFilename(t *testing.T) {
+	root := t.TempDir()
+	conn := &testConnector{scanDir: root, ignore: ignore.New(root)}
+
+	records := make(chan *connectors.Record, 1)
+	results := make(chan *connectors.Result, 1)
+
+	name := `weird\name.txt`
+	fi := objects.FileInfo{Lname: name}
+	records <- connectors.NewRecord(name, "", fi, nil, emptyReader)
+	close(records)
+
+	if err := conn.Export(context.Background(), records, results); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	want := filepath.Join(root, name)
+	if _, err := os.Stat(want); err != nil {
+		t.Fatalf("expected exported file at %s: %v", want, err)
+	}
+}
@@ -3,20 +3,25 @@ package importer
 import (
 	"context"
 	"fmt"
-	"io"
-	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
 
 	"github.com/PlakarKorp/kloset/connectors"
 	"github.com/PlakarKorp/kloset/connectors/importer"
 	"github.com/PlakarKorp/kloset/location"
-	"github.com/PlakarKorp/kloset/objects"
+
+	"github.com/tracepanic/plakar-integration/internal/ignore"
+	"github.com/tracepanic/plakar-integration/internal/walker"
 )
 
 type TestImporter struct {
-	scanDir string
+	scanDir        string
+	ignore         *ignore.IgnoreMatcher
+	followSymlinks bool
+	concurrency    int
 }
 
 func init() {
@@ -48,8 +53,25 @@ func NewTestImporter(ctx context.Context, opts *connectors.Options, name string,
 		return nil, fmt.Errorf("cannot access location: %w", err)
 	}
 
+	matcher := ignore.New(cleanPath)
+	if excludeFrom, ok := config["exclude_from"]; ok && excludeFrom != "" {
+		if err := matcher.LoadGlobalFile(excludeFrom); err != nil {
+			return nil, fmt.Errorf("cannot load exclude_from: %w", err)
+		}
+	}
+
+	followSymlinks, _ := strconv.ParseBool(config["follow_symlinks"])
+
+	concurrency := runtime.NumCPU()
+	if v, err := strconv.Atoi(config["concurrency"]); err == nil && v > 0 {
+		concurrency = v
+	}
+
 	return &TestImporter{
-		scanDir: cleanPath,
+		scanDir:        cleanPath,
+		ignore:         matcher,
+		followSymlinks: followSymlinks,
+		concurrency:    concurrency,
 	}, nil
 }
 
@@ -63,6 +85,9 @@ func (f *TestImporter) Ping(ctx context.Context) error {
 	return err
 }
 
+// Import walks scanDir with a bounded pool of worker goroutines instead
+// of a single filepath.WalkDir call. The walk itself is shared with
+// connector.testConnector via internal/walker.
 func (f *TestImporter) Import(ctx context.Context, records chan<- *connectors.Record, results <-chan *connectors.Result) error {
 	defer close(records)
 
@@ -70,34 +95,12 @@ func (f *TestImporter) Import(ctx context.Context, records chan<- *connectors.Re
 		return err
 	}
 
-	return filepath.WalkDir(f.scanDir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return nil // Skip errors
-		}
-
-		if d.IsDir() {
-			return nil // Skip directories
-		}
-
-		info, err := d.Info()
-		if err != nil {
-			return nil // Skip if can't stat
-		}
-
-		fi := objects.FileInfo{
-			Lname:    filepath.Base(path),
-			Lsize:    info.Size(),
-			Lmode:    info.Mode(),
-			LmodTime: info.ModTime(),
-			Ldev:     1,
-		}
-
-		records <- connectors.NewRecord(path, "", fi, nil, func() (io.ReadCloser, error) {
-			return os.Open(path)
-		})
-
-		return nil
-	})
+	return walker.Walk(ctx, walker.Options{
+		Root:           f.scanDir,
+		Ignore:         f.ignore,
+		FollowSymlinks: f.followSymlinks,
+		Concurrency:    f.concurrency,
+	}, records)
 }
 
 func (f *TestImporter) Close(ctx context.Context) error {
@@ -0,0 +1,205 @@
+// Package ignore implements gitignore-style path filtering for connectors
+// that walk a local filesystem tree.
+package ignore
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// pattern is a single parsed line from an ignore file.
+type pattern struct {
+	raw      string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+	segments []string
+}
+
+// IgnoreMatcher decides whether a path should be excluded from a walk. It
+// combines a set of global patterns (e.g. supplied via config) with
+// patterns loaded from .plakarignore files discovered while walking, each
+// scoped to the directory it was found in.
+type IgnoreMatcher struct {
+	root string
+
+	mu     sync.RWMutex
+	global []pattern
+	perDir map[string][]pattern
+}
+
+// New returns a matcher rooted at root. root is only used to resolve
+// .plakarignore files passed to LoadDirFile.
+func New(root string) *IgnoreMatcher {
+	return &IgnoreMatcher{
+		root:   root,
+		perDir: make(map[string][]pattern),
+	}
+}
+
+// LoadGlobalFile reads patterns from path (one per line) and adds them to
+// the matcher's global, always-applicable pattern set. It is meant for the
+// file referenced by config["exclude_from"].
+func (m *IgnoreMatcher) LoadGlobalFile(path string) error {
+	patterns, err := readPatterns(path)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.global = append(m.global, patterns...)
+	m.mu.Unlock()
+	return nil
+}
+
+// LoadDirFile loads a .plakarignore file from relDir (a slash-separated
+// path relative to the matcher's root, "" for the root itself) if one is
+// present. Its patterns only apply to paths under relDir. A missing file
+// is not an error.
+func (m *IgnoreMatcher) LoadDirFile(relDir string) error {
+	path := filepath.Join(m.root, filepath.FromSlash(relDir), ".plakarignore")
+
+	patterns, err := readPatterns(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	m.mu.Lock()
+	m.perDir[relDir] = append(m.perDir[relDir], patterns...)
+	m.mu.Unlock()
+	return nil
+}
+
+// Match reports whether relPath (slash-separated, relative to root)
+// should be excluded. isDir must reflect whether relPath names a
+// directory, since directory-only patterns (trailing "/") only match
+// directories.
+func (m *IgnoreMatcher) Match(relPath string, isDir bool) bool {
+	relPath = filepath.ToSlash(strings.TrimPrefix(relPath, "/"))
+	if relPath == "." || relPath == "" {
+		return false
+	}
+	segments := strings.Split(relPath, "/")
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ignored := applyPatterns(m.global, segments, isDir, false)
+
+	dir := ""
+	for i := range segments {
+		if pats, ok := m.perDir[dir]; ok {
+			ignored = applyPatterns(pats, segments[i:], isDir, ignored)
+		}
+		if dir == "" {
+			dir = segments[i]
+		} else {
+			dir = dir + "/" + segments[i]
+		}
+	}
+
+	return ignored
+}
+
+// applyPatterns evaluates pats against segments in order, gitignore-style:
+// the last matching pattern wins, and a "!" pattern negates a prior match.
+func applyPatterns(pats []pattern, segments []string, isDir, ignored bool) bool {
+	for _, p := range pats {
+		if p.dirOnly && !isDir {
+			continue
+		}
+
+		var matched bool
+		if p.anchored {
+			matched = matchSegments(p.segments, segments)
+		} else {
+			matched, _ = filepath.Match(p.segments[0], segments[len(segments)-1])
+		}
+
+		if matched {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
+
+// matchSegments matches an anchored, "/"-split pattern against a
+// "/"-split path, with "**" allowed to consume zero or more path
+// segments.
+func matchSegments(pat, path []string) bool {
+	if len(pat) == 0 {
+		return len(path) == 0
+	}
+
+	if pat[0] == "**" {
+		if matchSegments(pat[1:], path) {
+			return true
+		}
+		if len(path) == 0 {
+			return false
+		}
+		return matchSegments(pat, path[1:])
+	}
+
+	if len(path) == 0 {
+		return false
+	}
+
+	ok, _ := filepath.Match(pat[0], path[0])
+	if !ok {
+		return false
+	}
+	return matchSegments(pat[1:], path[1:])
+}
+
+func readPatterns(path string) ([]pattern, error) {
+	fp, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+
+	var patterns []pattern
+	scanner := bufio.NewScanner(fp)
+	for scanner.Scan() {
+		if p, ok := parsePattern(scanner.Text()); ok {
+			patterns = append(patterns, p)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return patterns, nil
+}
+
+func parsePattern(line string) (pattern, bool) {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return pattern{}, false
+	}
+
+	p := pattern{raw: trimmed}
+
+	if strings.HasPrefix(trimmed, "!") {
+		p.negate = true
+		trimmed = trimmed[1:]
+	}
+
+	if strings.HasSuffix(trimmed, "/") {
+		p.dirOnly = true
+		trimmed = strings.TrimSuffix(trimmed, "/")
+	}
+
+	anchored := strings.HasPrefix(trimmed, "/")
+	trimmed = strings.TrimPrefix(trimmed, "/")
+	p.anchored = anchored || strings.Contains(trimmed, "/")
+	p.segments = strings.Split(trimmed, "/")
+
+	return p, true
+}
@@ -0,0 +1,157 @@
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatchBasenamePattern(t *testing.T) {
+	root := t.TempDir()
+	m := New(root)
+	if err := m.LoadGlobalFile(writePatternFile(t, root, "*.log\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if !m.Match("debug.log", false) {
+		t.Error("expected debug.log to be ignored")
+	}
+	if !m.Match("sub/dir/debug.log", false) {
+		t.Error("expected a nested debug.log to be ignored by an unanchored pattern")
+	}
+	if m.Match("debug.log.bak", false) {
+		t.Error("did not expect debug.log.bak to match *.log")
+	}
+}
+
+func TestMatchAnchoredPattern(t *testing.T) {
+	root := t.TempDir()
+	m := New(root)
+	if err := m.LoadGlobalFile(writePatternFile(t, root, "/build\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if !m.Match("build", true) {
+		t.Error("expected root-anchored /build to match build at the root")
+	}
+	if m.Match("sub/build", true) {
+		t.Error("did not expect /build to match a nested build directory")
+	}
+}
+
+func TestMatchDirOnlyPattern(t *testing.T) {
+	root := t.TempDir()
+	m := New(root)
+	if err := m.LoadGlobalFile(writePatternFile(t, root, "out/\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if !m.Match("out", true) {
+		t.Error("expected directory-only pattern to match a directory named out")
+	}
+	if m.Match("out", false) {
+		t.Error("did not expect directory-only pattern to match a plain file named out")
+	}
+}
+
+func TestMatchDoubleStarPattern(t *testing.T) {
+	root := t.TempDir()
+	m := New(root)
+	if err := m.LoadGlobalFile(writePatternFile(t, root, "/a/**/z\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range []string{"a/z", "a/b/z", "a/b/c/z"} {
+		if !m.Match(p, false) {
+			t.Errorf("expected %q to match /a/**/z", p)
+		}
+	}
+	if m.Match("a/b/y", false) {
+		t.Error("did not expect a/b/y to match /a/**/z")
+	}
+}
+
+func TestMatchNegation(t *testing.T) {
+	root := t.TempDir()
+	m := New(root)
+	if err := m.LoadGlobalFile(writePatternFile(t, root, "*.log\n!keep.log\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if m.Match("keep.log", false) {
+		t.Error("expected !keep.log to un-ignore keep.log")
+	}
+	if !m.Match("other.log", false) {
+		t.Error("expected other.log to remain ignored")
+	}
+}
+
+func TestMatchCommentsAndBlankLinesIgnored(t *testing.T) {
+	root := t.TempDir()
+	m := New(root)
+	if err := m.LoadGlobalFile(writePatternFile(t, root, "# a comment\n\n*.tmp\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if !m.Match("a.tmp", false) {
+		t.Error("expected *.tmp to still be parsed around comments and blank lines")
+	}
+}
+
+// TestLoadDirFileScopesToItsDirectory verifies the nested-scope semantics
+// called out in the request this package shipped for: a .plakarignore
+// found at a/b/ only filters paths under a/b/, not sibling or ancestor
+// directories.
+func TestLoadDirFileScopesToItsDirectory(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "a", "b"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "a", "b", ".plakarignore"), []byte("*.cache\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := New(root)
+	if err := m.LoadDirFile("a/b"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !m.Match("a/b/x.cache", false) {
+		t.Error("expected a/b/x.cache to be ignored by a/b/.plakarignore")
+	}
+	if m.Match("a/x.cache", false) {
+		t.Error("did not expect a/.plakarignore-less a/x.cache to be ignored by a sibling scope")
+	}
+}
+
+func TestLoadDirFileMissingIsNotAnError(t *testing.T) {
+	root := t.TempDir()
+	m := New(root)
+	if err := m.LoadDirFile(""); err != nil {
+		t.Fatalf("expected a missing .plakarignore to be ignored, got %v", err)
+	}
+	if m.Match("anything", false) {
+		t.Error("expected no patterns to be loaded when .plakarignore is absent")
+	}
+}
+
+func TestMatchRootPathIsNeverIgnored(t *testing.T) {
+	root := t.TempDir()
+	m := New(root)
+	if err := m.LoadGlobalFile(writePatternFile(t, root, "*\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if m.Match("", false) || m.Match(".", false) {
+		t.Error("expected the walk root itself to never be reported as ignored")
+	}
+}
+
+func writePatternFile(t *testing.T, dir, contents string) string {
+	t.Helper()
+	path := filepath.Join(dir, "exclude_from")
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -0,0 +1,213 @@
+// Package walker implements the concurrent directory walk shared by this
+// module's importer and connector: a bounded worker pool that reads
+// directory entries via os.ReadDir and emits a connectors.Record per file,
+// relative to the walk root and forward-slash normalized.
+package walker
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/PlakarKorp/kloset/connectors"
+	"github.com/PlakarKorp/kloset/objects"
+
+	"github.com/tracepanic/plakar-integration/internal/ignore"
+)
+
+// Options configures a single Walk call.
+type Options struct {
+	// Root is the directory to walk. Record pathnames are emitted
+	// relative to it, forward-slash normalized, regardless of host OS.
+	Root string
+	// Ignore filters entries via its Match method, and has its
+	// .plakarignore files loaded as the walk descends into each directory.
+	Ignore *ignore.IgnoreMatcher
+	// FollowSymlinks resolves symlinks to their target instead of
+	// emitting a symlink record for them.
+	FollowSymlinks bool
+	// Concurrency bounds the number of worker goroutines walking
+	// directories concurrently.
+	Concurrency int
+}
+
+// Walk walks opts.Root with a bounded pool of worker goroutines instead of
+// a single filepath.WalkDir call: one directory's entries are read via
+// os.ReadDir, files are emitted as records directly, and subdirectories
+// are pushed back onto the work queue for any free worker to pick up.
+// This keeps I/O-bound trees (network filesystems, FUSE) from serializing
+// on a single goroutine's stat calls. Walk returns once every directory
+// has been visited or ctx is canceled, whichever comes first; it does not
+// close records.
+func Walk(ctx context.Context, opts Options, records chan<- *connectors.Record) error {
+	w := &walker{opts: opts}
+
+	g, gctx := errgroup.WithContext(ctx)
+
+	dirs := make(chan string)
+	var pending sync.WaitGroup
+
+	push := func(dir string) {
+		pending.Add(1)
+		go func() {
+			select {
+			case dirs <- dir:
+			case <-gctx.Done():
+				pending.Done()
+			}
+		}()
+	}
+
+	// pending is incremented synchronously before the closer goroutine
+	// starts, so it can never observe a zero count before the root
+	// directory is queued.
+	push(opts.Root)
+
+	go func() {
+		pending.Wait()
+		close(dirs)
+	}()
+
+	for i := 0; i < opts.Concurrency; i++ {
+		g.Go(func() error {
+			for dir := range dirs {
+				err := w.walkDirectory(gctx, dir, push, records)
+				pending.Done()
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	return g.Wait()
+}
+
+type walker struct {
+	opts Options
+}
+
+// walkDirectory lists a single directory's entries, emitting a record for
+// each file and handing each subdirectory to push for some worker to walk
+// next.
+func (w *walker) walkDirectory(ctx context.Context, dir string, push func(string), records chan<- *connectors.Record) error {
+	rel, err := filepath.Rel(w.opts.Root, dir)
+	if err != nil {
+		return nil
+	}
+	rel = filepath.ToSlash(rel)
+	dirRel := rel
+	if dirRel == "." {
+		dirRel = ""
+	}
+
+	// Load this directory's own .plakarignore before filtering its
+	// entries: a .plakarignore at a/b/ only affects files under a/b/.
+	// An unreadable .plakarignore is skipped; the directory is still walked.
+	_ = w.opts.Ignore.LoadDirFile(dirRel)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if dir == w.opts.Root {
+			return err
+		}
+		return nil // Skip unreadable subdirectories
+	}
+
+	for _, entry := range entries {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		entryRel := filepath.ToSlash(entry.Name())
+		if dirRel != "" {
+			entryRel = dirRel + "/" + entryRel
+		}
+
+		if entry.IsDir() {
+			if w.opts.Ignore.Match(entryRel, true) {
+				continue
+			}
+			push(path)
+			continue
+		}
+
+		if w.opts.Ignore.Match(entryRel, false) {
+			continue
+		}
+
+		w.emitFile(ctx, path, entryRel, records)
+	}
+
+	return nil
+}
+
+// emitFile stats a single file (or symlink, device, ...) and sends a
+// record for it. rel is path relative to Root, forward-slash normalized:
+// it is what ends up in Record.Pathname, so that an exporter can rejoin
+// it under a different root instead of replaying this host's absolute
+// path. Errors stating the file are treated as "skip", matching the rest
+// of the walk's best-effort error handling.
+func (w *walker) emitFile(ctx context.Context, path, rel string, records chan<- *connectors.Record) {
+	// Use Lstat, not Stat, so that symlinks are reported as symlinks
+	// instead of being resolved to their target.
+	info, err := os.Lstat(path)
+	if err != nil {
+		return
+	}
+
+	mode := info.Mode()
+	var target string
+
+	if mode&fs.ModeSymlink != 0 {
+		if w.opts.FollowSymlinks {
+			resolved, err := os.Stat(path)
+			if err != nil {
+				return // dangling symlink or loop, skip
+			}
+			info = resolved
+			mode = info.Mode()
+		} else {
+			t, err := os.Readlink(path)
+			if err != nil {
+				return
+			}
+			target = t
+		}
+	}
+
+	fi := objects.FileInfo{
+		Lname:    filepath.Base(path),
+		Lsize:    info.Size(),
+		Lmode:    mode,
+		LmodTime: info.ModTime(),
+		Ldev:     1,
+	}
+
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		fi.Ldev = uint64(st.Dev)
+		fi.Lino = st.Ino
+	}
+
+	record := connectors.NewRecord(rel, target, fi, nil, func() (io.ReadCloser, error) {
+		if !mode.IsRegular() {
+			return nil, nil
+		}
+		return os.Open(path)
+	})
+
+	select {
+	case records <- record:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,70 @@
+package connector
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// windowsReservedNames are filenames Windows refuses to create regardless
+// of extension or case (CON, CON.txt, con, ...).
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// HasPathPrefix reports whether candidate is base itself or a descendant
+// of it, comparing path components rather than raw bytes so that
+// "/tmp/foo" is not considered a prefix of "/tmp/foobar". Both paths are
+// expected to already be absolute and cleaned (e.g. via filepath.Abs).
+func HasPathPrefix(base, candidate string) bool {
+	if base == candidate {
+		return true
+	}
+
+	sep := string(filepath.Separator)
+	if !strings.HasSuffix(base, sep) {
+		base += sep
+	}
+	return strings.HasPrefix(candidate, base)
+}
+
+// validatePathname rejects record pathnames that cannot be safely joined
+// under an export root: NUL bytes always, and, when exporting onto
+// Windows, Windows-reserved device names and drive-letter absolute paths
+// smuggled in through a Unix-style pathname. Those two checks are
+// Windows-only: "con", "prn.bak", "aux", etc. are perfectly legal
+// filenames on Unix, and rejecting them there would break restores of
+// otherwise-valid backups.
+func validatePathname(pathname string) error {
+	if strings.IndexByte(pathname, 0) >= 0 {
+		return fmt.Errorf("pathname contains a NUL byte")
+	}
+
+	if runtime.GOOS != "windows" {
+		return nil
+	}
+
+	if len(pathname) >= 2 && pathname[1] == ':' {
+		c := pathname[0]
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') {
+			return fmt.Errorf("pathname %q looks like a Windows absolute path", pathname)
+		}
+	}
+
+	for _, segment := range strings.Split(filepath.ToSlash(pathname), "/") {
+		name := segment
+		if i := strings.IndexByte(name, '.'); i >= 0 {
+			name = name[:i]
+		}
+		if windowsReservedNames[strings.ToUpper(name)] {
+			return fmt.Errorf("pathname %q contains a reserved name %q", pathname, segment)
+		}
+	}
+
+	return nil
+}
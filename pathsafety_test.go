@@ -0,0 +1,94 @@
+package connector
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/PlakarKorp/kloset/connectors"
+	"github.com/PlakarKorp/kloset/objects"
+)
+
+func TestHasPathPrefix(t *testing.T) {
+	cases := []struct {
+		base, candidate string
+		want            bool
+	}{
+		{"/tmp/foo", "/tmp/foo", true},
+		{"/tmp/foo", "/tmp/foo/bar", true},
+		{"/tmp/foo", "/tmp/foobar", false},
+		{"/tmp/foo", "/tmp/fo", false},
+		{"/tmp/foo", "/tmp", false},
+	}
+
+	for _, c := range cases {
+		if got := HasPathPrefix(c.base, c.candidate); got != c.want {
+			t.Errorf("HasPathPrefix(%q, %q) = %v, want %v", c.base, c.candidate, got, c.want)
+		}
+	}
+}
+
+func TestValidatePathnameRejectsNulByte(t *testing.T) {
+	if err := validatePathname("foo\x00bar"); err == nil {
+		t.Fatal("expected an error for a pathname containing a NUL byte")
+	}
+}
+
+func TestValidatePathnameAcceptsOrdinaryPaths(t *testing.T) {
+	for _, p := range []string{"a/b/c.txt", "../../etc/passwd", "weird\\name.txt", "con.txt"} {
+		if err := validatePathname(p); err != nil && runtime.GOOS != "windows" {
+			t.Errorf("validatePathname(%q) = %v, want nil on %s", p, err, runtime.GOOS)
+		}
+	}
+}
+
+func TestValidatePathnameWindowsChecksAreGated(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("these checks are expected to fire on windows")
+	}
+
+	for _, p := range []string{"con", "con.txt", "PRN.log", "aux", "com1.old", `C:\foo\bar`} {
+		if err := validatePathname(p); err != nil {
+			t.Errorf("validatePathname(%q) = %v, want nil on non-Windows host", p, err)
+		}
+	}
+}
+
+// TestExportRejectsPathTraversal exercises the adversarial pathnames this
+// request calls out: pathnames that, after filepath.Join + Clean, would
+// resolve outside the export root.
+func TestExportRejectsPathTraversal(t *testing.T) {
+	adversarial := []string{
+		"../../etc/passwd",
+		"/../../etc/passwd",
+		"a/../../../etc/passwd",
+	}
+
+	for _, pathname := range adversarial {
+		root := t.TempDir()
+		conn := &testConnector{scanDir: root}
+
+		records := make(chan *connectors.Record, 1)
+		results := make(chan *connectors.Result, 1)
+
+		fi := objects.FileInfo{Lname: filepath.Base(pathname)}
+		records <- connectors.NewRecord(pathname, "", fi, nil, func() (io.ReadCloser, error) {
+			return nil, nil
+		})
+		close(records)
+
+		if err := conn.Export(context.Background(), records, results); err != nil {
+			t.Fatalf("Export(%q): %v", pathname, err)
+		}
+		<-results // drain the single result Export produced for our one record
+
+		// Each pathname climbs two levels above root.
+		escaped := filepath.Join(filepath.Dir(filepath.Dir(root)), "etc", "passwd")
+		if _, err := os.Stat(escaped); err == nil {
+			t.Errorf("pathname %q escaped the export root onto disk", pathname)
+		}
+	}
+}